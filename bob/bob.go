@@ -24,6 +24,7 @@ type Constructor func(http.RoundTripper) http.RoundTripper
 // the same set of constructors in the same order.
 type Chain struct {
 	constructors []Constructor
+	endwares     []Endware
 }
 
 // New creates a new chain,
@@ -31,7 +32,26 @@ type Chain struct {
 // New serves no other function,
 // constructors are only called upon a call to Then().
 func New(constructors ...Constructor) Chain {
-	return Chain{append(([]Constructor)(nil), constructors...)}
+	return Chain{append(([]Constructor)(nil), constructors...), nil}
+}
+
+// endwareRoundTripper represents a RoundTripper that has been modified to
+// run endwares after RoundTrip returns. This is a helper for Then(),
+// mirroring alice's endwareHandler.
+type endwareRoundTripper struct {
+	rt       http.RoundTripper
+	endwares []Endware
+}
+
+// RoundTrip calls the wrapped RoundTripper, then calls every endware with
+// the request and whatever RoundTrip returned, including when it returned
+// a non-nil error and therefore a nil response.
+func (ert endwareRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := ert.rt.RoundTrip(r)
+	for _, endware := range ert.endwares {
+		endware(r, resp, err)
+	}
+	return resp, err
 }
 
 // Then chains the middleware and returns the final http.RoundTripper.
@@ -57,6 +77,10 @@ func (c Chain) Then(rt http.RoundTripper) http.RoundTripper {
 		rt = http.DefaultTransport
 	}
 
+	if len(c.endwares) > 0 {
+		rt = endwareRoundTripper{rt, c.endwares}
+	}
+
 	for i := range c.constructors {
 		rt = c.constructors[len(c.constructors)-1-i](rt)
 	}
@@ -93,7 +117,7 @@ func (c Chain) Append(constructors ...Constructor) Chain {
 	newCons = append(newCons, c.constructors...)
 	newCons = append(newCons, constructors...)
 
-	return Chain{newCons}
+	return Chain{newCons, c.endwares}
 }
 
 // Extend extends a chain by adding the specified chain
@@ -118,5 +142,46 @@ func (c Chain) Append(constructors ...Constructor) Chain {
 //		// requests to aHtml hitting nosurfs success handler go m1 -> nosurf -> m2 -> target-roundtripper
 //		// requests to aHtml hitting nosurfs failure handler go m1 -> nosurf -> m2 -> csrfFail
 func (c Chain) Extend(chain Chain) Chain {
-	return c.Append(chain.constructors...)
+	return c.Append(chain.constructors...).AppendEndware(chain.endwares...)
+}
+
+// Endware is functionality executed after RoundTrip returns and a result
+// -- a response, an error, or both -- is available. Typical endwares
+// record metrics, account for response body size, or write to a
+// response cache. Because RoundTrip can fail without producing a
+// response, an endware must handle resp being nil when err is not.
+type Endware func(req *http.Request, resp *http.Response, err error)
+
+// After creates a new chain with the original chain's constructors and
+// endwares, as well as the provided endwares. Endwares are run, in order,
+// once RoundTrip returns.
+func (c Chain) After(endwares ...Endware) Chain {
+	newEnds := make([]Endware, 0, len(c.endwares)+len(endwares))
+	newEnds = append(newEnds, c.endwares...)
+	newEnds = append(newEnds, endwares...)
+
+	return Chain{c.constructors, newEnds}
+}
+
+// AfterFuncs works identically to After, but takes plain functions
+// instead of Endwares.
+//
+// The following two statements are equivalent:
+//     c.After(bob.Endware(fn1), bob.Endware(fn2))
+//     c.AfterFuncs(fn1, fn2)
+func (c Chain) AfterFuncs(fns ...func(req *http.Request, resp *http.Response, err error)) Chain {
+	endwares := make([]Endware, len(fns))
+	for i, fn := range fns {
+		endwares[i] = Endware(fn)
+	}
+
+	return c.After(endwares...)
+}
+
+// AppendEndware extends a chain, adding the specified endwares as the
+// last ones to run once RoundTrip returns.
+//
+// AppendEndware returns a new chain, leaving the original one untouched.
+func (c Chain) AppendEndware(endwares ...Endware) Chain {
+	return c.After(endwares...)
 }
@@ -0,0 +1,81 @@
+package bob
+
+import "net/http"
+
+// ConstructorE is to ChainE what Constructor is to Chain, except it may
+// fail to build its http.RoundTripper. This lets middleware that
+// validates configuration -- parsing a proxy URL, loading TLS material
+// -- report the failure when the chain is built, instead of panicking
+// or deferring the check to request time.
+type ConstructorE func(http.RoundTripper) (http.RoundTripper, error)
+
+// ToConstructorE adapts a plain Constructor -- which cannot fail -- into
+// a ConstructorE, so it can be mixed into a ChainE alongside ones that
+// can.
+func ToConstructorE(c Constructor) ConstructorE {
+	return func(rt http.RoundTripper) (http.RoundTripper, error) {
+		return c(rt), nil
+	}
+}
+
+// ChainE acts as a list of ConstructorE. ChainE is effectively immutable:
+// once created, it will always hold the same set of constructors in the
+// same order.
+type ChainE struct {
+	constructors []ConstructorE
+}
+
+// NewE creates a new ChainE,
+// memorizing the given list of middleware constructors.
+// NewE serves no other function,
+// constructors are only called upon a call to ThenE().
+func NewE(constructors ...ConstructorE) ChainE {
+	return ChainE{append(([]ConstructorE)(nil), constructors...)}
+}
+
+// ThenE chains the middleware and returns the final http.RoundTripper.
+//     NewE(m1, m2, m3).ThenE(rt)
+// is equivalent to:
+//     m1(m2(m3(rt)))
+// with constructors applied tail to head, same as Then.
+//
+// Unlike Chain.Then, ThenE can fail: if any constructor returns an
+// error, ThenE stops immediately and returns that error together with a
+// nil http.RoundTripper.
+//
+// ThenE() treats nil as http.DefaultTransport, same as Then().
+func (c ChainE) ThenE(rt http.RoundTripper) (http.RoundTripper, error) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	var err error
+	for i := range c.constructors {
+		rt, err = c.constructors[len(c.constructors)-1-i](rt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rt, nil
+}
+
+// AppendE extends a chain, adding the specified constructors
+// as the last ones in the request flow.
+//
+// AppendE returns a new chain, leaving the original one untouched.
+func (c ChainE) AppendE(constructors ...ConstructorE) ChainE {
+	newCons := make([]ConstructorE, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return ChainE{newCons}
+}
+
+// ExtendE extends a chain by adding the specified chain
+// as the last one in the request flow.
+//
+// ExtendE returns a new chain, leaving the original one untouched.
+func (c ChainE) ExtendE(chain ChainE) ChainE {
+	return c.AppendE(chain.constructors...)
+}
@@ -0,0 +1,123 @@
+package bob
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func tagMiddlewareE(tag string) ConstructorE {
+	return func(rt http.RoundTripper) (http.RoundTripper, error) {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if err := appendTag(tag, r); err != nil {
+				return nil, err
+			}
+			return rt.RoundTrip(r)
+		}), nil
+	}
+}
+
+func failingMiddlewareE(err error) ConstructorE {
+	return func(rt http.RoundTripper) (http.RoundTripper, error) {
+		return nil, err
+	}
+}
+
+func TestNewE(t *testing.T) {
+	c1 := func(rt http.RoundTripper) (http.RoundTripper, error) { return rt, nil }
+	c2 := func(rt http.RoundTripper) (http.RoundTripper, error) { return rt, nil }
+
+	slice := []ConstructorE{c1, c2}
+	chain := NewE(slice...)
+
+	for k := range slice {
+		if !funcsEqual(chain.constructors[k], slice[k]) {
+			t.Error("NewE does not add constructors correctly")
+		}
+	}
+}
+
+func TestThenETreatsNilAsDefaultTransport(t *testing.T) {
+	rt, err := NewE().ThenE(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt != http.DefaultTransport {
+		t.Error("ThenE does not treat nil as DefaultTransport")
+	}
+}
+
+func TestThenEOrdersRoundTrippersCorrectly(t *testing.T) {
+	chained, err := NewE(
+		tagMiddlewareE("t1\n"),
+		tagMiddlewareE("t2\n"),
+		tagMiddlewareE("t3\n"),
+	).ThenE(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	chained.RoundTrip(r)
+
+	body, err := bodyAsString(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "t1\nt2\nt3\napp\n" {
+		t.Error("ThenE does not order round trippers correctly")
+	}
+}
+
+func TestThenEPropagatesConstructorError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	rt, err := NewE(
+		tagMiddlewareE("t1\n"),
+		failingMiddlewareE(wantErr),
+		tagMiddlewareE("t2\n"),
+	).ThenE(testApp)
+
+	if err != wantErr {
+		t.Errorf("ThenE returned error %v, want %v", err, wantErr)
+	}
+	if rt != nil {
+		t.Error("ThenE should return a nil http.RoundTripper on error")
+	}
+}
+
+func TestAppendEAddsRoundTrippersCorrectly(t *testing.T) {
+	chain := NewE(tagMiddlewareE("t1\n"), tagMiddlewareE("t2\n"))
+	newChain := chain.AppendE(tagMiddlewareE("t3\n"), tagMiddlewareE("t4\n"))
+
+	if len(chain.constructors) != 2 {
+		t.Error("chain should have 2 constructors")
+	}
+	if len(newChain.constructors) != 4 {
+		t.Error("newChain should have 4 constructors")
+	}
+}
+
+func TestToConstructorEAdaptsConstructor(t *testing.T) {
+	chained, err := NewE(ToConstructorE(tagMiddleware("t1\n"))).ThenE(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	chained.RoundTrip(r)
+
+	body, err := bodyAsString(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "t1\napp\n" {
+		t.Errorf("ToConstructorE did not adapt the Constructor correctly: got %q", body)
+	}
+}
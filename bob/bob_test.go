@@ -3,6 +3,7 @@ package bob
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"reflect"
@@ -219,3 +220,68 @@ func TestExtendRespectsImmutability(t *testing.T) {
 		t.Error("Extend does not respect immutability")
 	}
 }
+
+func TestAfterRunsEndwaresAfterRoundTrip(t *testing.T) {
+	var order []string
+
+	chained := New(tagMiddleware("t1\n")).After(
+		func(req *http.Request, resp *http.Response, err error) {
+			order = append(order, "e1")
+		},
+		func(req *http.Request, resp *http.Response, err error) {
+			order = append(order, "e2")
+		},
+	).Then(testApp)
+
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.RoundTrip(r)
+
+	if len(order) != 2 || order[0] != "e1" || order[1] != "e2" {
+		t.Errorf("After did not run endwares in order, got %v", order)
+	}
+}
+
+func TestAfterEndwareSeesErrorAndNilResponse(t *testing.T) {
+	wantErr := errors.New("round trip failed")
+	var gotResp *http.Response
+	var gotErr error
+
+	failing := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, wantErr
+	})
+
+	chained := New().AfterFuncs(func(req *http.Request, resp *http.Response, err error) {
+		gotResp = resp
+		gotErr = err
+	}).Then(failing)
+
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chained.RoundTrip(r)
+
+	if gotErr != wantErr {
+		t.Errorf("endware got error %v, want %v", gotErr, wantErr)
+	}
+	if gotResp != nil {
+		t.Error("endware should see a nil response when RoundTrip fails")
+	}
+}
+
+func TestAppendEndwareRespectsImmutability(t *testing.T) {
+	chain := New().After(func(req *http.Request, resp *http.Response, err error) {})
+	newChain := chain.AppendEndware(func(req *http.Request, resp *http.Response, err error) {})
+
+	if len(chain.endwares) != 1 {
+		t.Error("chain should have 1 endware")
+	}
+	if len(newChain.endwares) != 2 {
+		t.Error("newChain should have 2 endwares")
+	}
+}
@@ -0,0 +1,101 @@
+package bob
+
+import "net/http"
+
+// Call performs a single round trip: the same shape as
+// http.RoundTripper.RoundTrip, but exposed as a plain function so
+// middleware can be written as a wrapper around it instead of having to
+// implement RoundTripper by hand.
+type Call func(*http.Request) (*http.Response, error)
+
+// CallWrapper wraps a Call with another one, the same way Constructor
+// wraps an http.RoundTripper. Unlike Constructor, both the outer and
+// inner Call share the same signature, so a CallWrapper can freely
+// observe or replace the response and error that come back --
+// something a plain Constructor can only do by hand-writing a
+// RoundTripperFunc that calls the wrapped RoundTripper itself. This
+// makes CallWrapper a natural fit for retry-on-5xx with backoff,
+// response caching, decompression, latency/status metrics, and circuit
+// breakers.
+type CallWrapper func(Call) Call
+
+// CallChain acts as a list of CallWrapper.
+// CallChain is effectively immutable:
+// once created, it will always hold
+// the same set of wrappers in the same order.
+type CallChain struct {
+	wrappers []CallWrapper
+}
+
+// NewCall creates a new CallChain,
+// memorizing the given list of CallWrappers.
+// NewCall serves no other function,
+// wrappers are only called upon a call to Then().
+func NewCall(wrappers ...CallWrapper) CallChain {
+	return CallChain{append(([]CallWrapper)(nil), wrappers...)}
+}
+
+// LiftConstructor adapts a plain Constructor into a CallWrapper, so
+// ordinary bob middleware -- which cannot observe the response -- can be
+// mixed into a CallChain alongside wrappers that can.
+func LiftConstructor(c Constructor) CallWrapper {
+	return func(next Call) Call {
+		return c(RoundTripperFunc(next)).RoundTrip
+	}
+}
+
+// Then chains the CallWrappers around rt and returns the final
+// http.RoundTripper.
+//     NewCall(w1, w2, w3).Then(rt)
+// is equivalent to:
+//     w1(w2(w3(rt.RoundTrip)))
+// When the request goes out, it will be passed to w1, then w2, then w3
+// and finally to rt's RoundTrip (assuming every wrapper calls the
+// following one).
+//
+// Then() treats nil as http.DefaultTransport.
+func (cc CallChain) Then(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	if len(cc.wrappers) == 0 {
+		return rt
+	}
+
+	call := rt.RoundTrip
+	for i := range cc.wrappers {
+		call = cc.wrappers[len(cc.wrappers)-1-i](call)
+	}
+
+	return RoundTripperFunc(call)
+}
+
+// ThenFunc works identically to Then, but takes
+// a RoundTripperFunc instead of an http.RoundTripper.
+func (cc CallChain) ThenFunc(fn RoundTripperFunc) http.RoundTripper {
+	if fn == nil {
+		return cc.Then(nil)
+	}
+	return cc.Then(fn)
+}
+
+// Append extends a chain, adding the specified wrappers
+// as the last ones in the request flow.
+//
+// Append returns a new chain, leaving the original one untouched.
+func (cc CallChain) Append(wrappers ...CallWrapper) CallChain {
+	newWrappers := make([]CallWrapper, 0, len(cc.wrappers)+len(wrappers))
+	newWrappers = append(newWrappers, cc.wrappers...)
+	newWrappers = append(newWrappers, wrappers...)
+
+	return CallChain{newWrappers}
+}
+
+// Extend extends a chain by adding the specified chain's wrappers
+// as the last ones in the request flow.
+//
+// Extend returns a new chain, leaving the original one untouched.
+func (cc CallChain) Extend(chain CallChain) CallChain {
+	return cc.Append(chain.wrappers...)
+}
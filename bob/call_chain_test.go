@@ -0,0 +1,117 @@
+package bob
+
+import (
+	"net/http"
+	"testing"
+)
+
+func tagCallWrapper(tag string) CallWrapper {
+	return func(next Call) Call {
+		return func(r *http.Request) (*http.Response, error) {
+			if err := appendTag(tag, r); err != nil {
+				return nil, err
+			}
+			return next(r)
+		}
+	}
+}
+
+func TestCallChainThenWorksWithNoWrappers(t *testing.T) {
+	if !funcsEqual(NewCall().Then(testApp), testApp) {
+		t.Error("Then does not work with no wrappers")
+	}
+}
+
+func TestCallChainThenTreatsNilAsDefaultTransport(t *testing.T) {
+	if NewCall().Then(nil) != http.DefaultTransport {
+		t.Error("Then does not treat nil as DefaultTransport")
+	}
+}
+
+func TestCallChainThenOrdersWrappersCorrectly(t *testing.T) {
+	chained := NewCall(
+		tagCallWrapper("t1\n"),
+		tagCallWrapper("t2\n"),
+		tagCallWrapper("t3\n"),
+	).Then(testApp)
+
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chained.RoundTrip(r)
+
+	body, err := bodyAsString(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "t1\nt2\nt3\napp\n" {
+		t.Error("Then does not order wrappers correctly")
+	}
+}
+
+func TestCallChainAppendAddsWrappersCorrectly(t *testing.T) {
+	chain := NewCall(tagCallWrapper("t1\n"), tagCallWrapper("t2\n"))
+	newChain := chain.Append(tagCallWrapper("t3\n"), tagCallWrapper("t4\n"))
+
+	if len(chain.wrappers) != 2 {
+		t.Error("chain should have 2 wrappers")
+	}
+	if len(newChain.wrappers) != 4 {
+		t.Error("newChain should have 4 wrappers")
+	}
+
+	chained := newChain.Then(testApp)
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chained.RoundTrip(r)
+
+	body, err := bodyAsString(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "t1\nt2\nt3\nt4\napp\n" {
+		t.Error("Append does not add wrappers correctly")
+	}
+}
+
+func TestCallChainExtendAddsWrappersCorrectly(t *testing.T) {
+	chain1 := NewCall(tagCallWrapper("t1\n"), tagCallWrapper("t2\n"))
+	chain2 := NewCall(tagCallWrapper("t3\n"), tagCallWrapper("t4\n"))
+	newChain := chain1.Extend(chain2)
+
+	chained := newChain.Then(testApp)
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chained.RoundTrip(r)
+
+	body, err := bodyAsString(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "t1\nt2\nt3\nt4\napp\n" {
+		t.Error("Extend does not add wrappers in correctly")
+	}
+}
+
+func TestLiftConstructorAdaptsConstructor(t *testing.T) {
+	chained := NewCall(LiftConstructor(tagMiddleware("t1\n"))).Then(testApp)
+
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chained.RoundTrip(r)
+
+	body, err := bodyAsString(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "t1\napp\n" {
+		t.Errorf("LiftConstructor did not adapt the Constructor correctly: got %q", body)
+	}
+}
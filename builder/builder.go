@@ -0,0 +1,138 @@
+// Package builder lets middleware and endwares be registered by name and
+// assembled into an alice.Chain from plain string names, so a chain can
+// be driven by configuration (YAML, JSON, env vars) instead of Go code
+// wiring together alice.New(...).Append(...) calls per route.
+package builder
+
+import (
+	"fmt"
+
+	"github.com/justinas/alice"
+)
+
+// entry is something Build can expand a name into: either a concrete
+// Constructor, or a composite that expands to a list of other names.
+type entry struct {
+	isComposite bool
+	constructor alice.Constructor
+	composite   []string
+}
+
+// Builder is a registry of named Constructors, Endwares and composite
+// groups. The zero value is not usable; create one with New.
+//
+// Builder is not safe for concurrent registration, but Build may be
+// called concurrently once registration is done.
+type Builder struct {
+	constructors map[string]entry
+	endwares     map[string]alice.Endware
+}
+
+// New creates an empty Builder.
+func New() *Builder {
+	return &Builder{
+		constructors: make(map[string]entry),
+		endwares:     make(map[string]alice.Endware),
+	}
+}
+
+// Register associates name with a middleware Constructor, so it can
+// later be referenced by name in Build.
+//
+// Register returns an error if name is already registered, as either a
+// constructor or a composite.
+func (b *Builder) Register(name string, c alice.Constructor) error {
+	if _, ok := b.constructors[name]; ok {
+		return fmt.Errorf("builder: %q is already registered", name)
+	}
+	b.constructors[name] = entry{constructor: c}
+	return nil
+}
+
+// RegisterComposite associates name with a group of other registered
+// names, which Build expands in place wherever name is used. This lets
+// callers define reusable groupings, e.g.
+//     b.RegisterComposite("standard", []string{"recover", "gzip", "requestid"})
+//
+// RegisterComposite returns an error if name is already registered, as
+// either a constructor or a composite.
+func (b *Builder) RegisterComposite(name string, names []string) error {
+	if _, ok := b.constructors[name]; ok {
+		return fmt.Errorf("builder: %q is already registered", name)
+	}
+	b.constructors[name] = entry{
+		isComposite: true,
+		composite:   append([]string(nil), names...),
+	}
+	return nil
+}
+
+// RegisterEndware associates name with an Endware, so it can later be
+// referenced by name in Build.
+//
+// RegisterEndware returns an error if name is already registered.
+func (b *Builder) RegisterEndware(name string, e alice.Endware) error {
+	if _, ok := b.endwares[name]; ok {
+		return fmt.Errorf("builder: endware %q is already registered", name)
+	}
+	b.endwares[name] = e
+	return nil
+}
+
+// Build assembles an alice.Chain from the given constructor and endware
+// names, in order, expanding composites along the way.
+//
+// Build returns an error if any name is unknown, or if a composite
+// refers back to itself, directly or through another composite.
+func (b *Builder) Build(constructorNames, endwareNames []string) (alice.Chain, error) {
+	constructors, err := b.expand(constructorNames, nil)
+	if err != nil {
+		return alice.Chain{}, err
+	}
+
+	endwares := make([]alice.Endware, 0, len(endwareNames))
+	for _, name := range endwareNames {
+		e, ok := b.endwares[name]
+		if !ok {
+			return alice.Chain{}, fmt.Errorf("builder: unknown endware %q", name)
+		}
+		endwares = append(endwares, e)
+	}
+
+	return alice.New(constructors...).After(endwares...), nil
+}
+
+// expand resolves names into a flat list of Constructors, recursing into
+// composites. seen holds the composite names already being expanded on
+// the current path, so a composite that (directly or transitively)
+// contains itself is reported as an error instead of recursing forever.
+func (b *Builder) expand(names []string, seen map[string]bool) ([]alice.Constructor, error) {
+	constructors := make([]alice.Constructor, 0, len(names))
+	for _, name := range names {
+		e, ok := b.constructors[name]
+		if !ok {
+			return nil, fmt.Errorf("builder: unknown constructor %q", name)
+		}
+
+		if !e.isComposite {
+			constructors = append(constructors, e.constructor)
+			continue
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("builder: composite %q is part of a cycle", name)
+		}
+		nextSeen := make(map[string]bool, len(seen)+1)
+		for k := range seen {
+			nextSeen[k] = true
+		}
+		nextSeen[name] = true
+
+		sub, err := b.expand(e.composite, nextSeen)
+		if err != nil {
+			return nil, err
+		}
+		constructors = append(constructors, sub...)
+	}
+	return constructors, nil
+}
@@ -0,0 +1,116 @@
+package builder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/justinas/alice"
+)
+
+func tagMiddleware(tag string) alice.Constructor {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tag))
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+func tagEndware(tag string) alice.Endware {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(tag))
+	})
+}
+
+var testApp = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("app\n"))
+})
+
+func TestBuildAssemblesChainFromNames(t *testing.T) {
+	b := New()
+	if err := b.Register("m1", tagMiddleware("m1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Register("m2", tagMiddleware("m2\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RegisterEndware("e1", tagEndware("e1\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := b.Build([]string{"m1", "m2"}, []string{"e1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	chain.ThenFunc(testApp).ServeHTTP(w, r)
+
+	if w.Body.String() != "m1\nm2\napp\ne1\n" {
+		t.Errorf("Build did not assemble the chain correctly: got %q", w.Body.String())
+	}
+}
+
+func TestBuildExpandsComposites(t *testing.T) {
+	b := New()
+	_ = b.Register("recover", tagMiddleware("recover\n"))
+	_ = b.Register("gzip", tagMiddleware("gzip\n"))
+	_ = b.RegisterComposite("standard", []string{"recover", "gzip"})
+	_ = b.Register("auth", tagMiddleware("auth\n"))
+
+	chain, err := b.Build([]string{"standard", "auth"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	chain.ThenFunc(testApp).ServeHTTP(w, r)
+
+	if w.Body.String() != "recover\ngzip\nauth\napp\n" {
+		t.Errorf("Build did not expand the composite correctly: got %q", w.Body.String())
+	}
+}
+
+func TestBuildReturnsErrorForUnknownName(t *testing.T) {
+	b := New()
+	if _, err := b.Build([]string{"missing"}, nil); err == nil {
+		t.Error("Build should error on an unknown constructor name")
+	}
+
+	_ = b.Register("m1", tagMiddleware("m1\n"))
+	if _, err := b.Build([]string{"m1"}, []string{"missing"}); err == nil {
+		t.Error("Build should error on an unknown endware name")
+	}
+}
+
+func TestRegisterRejectsDuplicateNames(t *testing.T) {
+	b := New()
+	if err := b.Register("m1", tagMiddleware("m1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Register("m1", tagMiddleware("m1\n")); err == nil {
+		t.Error("Register should error on a duplicate name")
+	}
+	if err := b.RegisterComposite("m1", []string{}); err == nil {
+		t.Error("RegisterComposite should error when the name is already a constructor")
+	}
+}
+
+func TestBuildDetectsCompositeCycles(t *testing.T) {
+	b := New()
+	_ = b.RegisterComposite("a", []string{"b"})
+	_ = b.RegisterComposite("b", []string{"a"})
+
+	if _, err := b.Build([]string{"a"}, nil); err == nil {
+		t.Error("Build should detect a cycle between composites")
+	}
+}
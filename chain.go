@@ -3,6 +3,7 @@ package alice
 
 import (
 	"net/http"
+	"reflect"
 )
 
 // A constructor for a piece of middleware.
@@ -17,6 +18,7 @@ type Constructor func(http.Handler) http.Handler
 type Chain struct {
 	constructors []Constructor
 	endwares     []Endware
+	providers    map[reflect.Type]reflect.Value
 }
 
 // New creates a new chain,
@@ -24,7 +26,7 @@ type Chain struct {
 // New serves no other function,
 // constructors are only called upon a call to Then().
 func New(constructors ...Constructor) Chain {
-	return Chain{append(([]Constructor)(nil), constructors...), ([]Endware)(nil)}
+	return Chain{append(([]Constructor)(nil), constructors...), ([]Endware)(nil), nil}
 }
 
 // endwareHandler represents a handler that has been modified
@@ -97,6 +99,17 @@ func (c Chain) ThenFunc(fn http.HandlerFunc) http.Handler {
 	return c.Then(fn)
 }
 
+// copy returns a shallow copy of c. It backs Chain.Contextualise, which
+// needs a snapshot of the chain as it existed before contextualisation
+// without aliasing the original's slices.
+func (c Chain) copy() Chain {
+	return Chain{
+		append(([]Constructor)(nil), c.constructors...),
+		append(([]Endware)(nil), c.endwares...),
+		c.providers,
+	}
+}
+
 // Append extends a chain, adding the specified constructors
 // as the last ones in the request flow.
 //
@@ -112,7 +125,9 @@ func (c Chain) Append(constructors ...Constructor) Chain {
 	newCons = append(newCons, c.constructors...)
 	newCons = append(newCons, constructors...)
 
-	return New(newCons...).AppendEndware(c.endwares...)
+	newEnds := append(([]Endware)(nil), c.endwares...)
+
+	return Chain{newCons, newEnds, c.providers}
 }
 
 // Extend extends a chain by adding the specified chain
@@ -142,7 +157,8 @@ func (c Chain) Append(constructors ...Constructor) Chain {
 func (c Chain) Extend(chain Chain) Chain {
 	return c.
 		Append(chain.constructors...).
-		AppendEndware(chain.endwares...)
+		AppendEndware(chain.endwares...).
+		withMergedProviders(chain.providers)
 }
 
 // Endware is functionality executed after a the main handler is called
@@ -161,9 +177,9 @@ func (c Chain) After(endwares ...Endware) Chain {
 	newEnds = append(newEnds, c.endwares...)
 	newEnds = append(newEnds, endwares...)
 
-	newC := New(c.constructors...)
-	newC.endwares = newEnds
-	return newC
+	newCons := append(([]Constructor)(nil), c.constructors...)
+
+	return Chain{newCons, newEnds, c.providers}
 }
 
 // AfterFuncs works identically to After, but takes HandlerFuncs
@@ -195,7 +211,7 @@ func (c Chain) AfterFuncs(fns ...func(w http.ResponseWriter, r *http.Request)) C
 //     // requests in stdHandler go m1 -> handler -> e1 -> e2
 //     // requests in extHandler go m1 -> handler -> e1 -> e2 -> e3 -> e4
 func (c Chain) AppendEndware(endwares ...Endware) Chain {
-	return New(c.constructors...).After(append(c.endwares, endwares...)...)
+	return c.After(endwares...)
 }
 
 // AppendEndwareFuncs works identically to AppendEndware, but takes HandlerFuncs
@@ -0,0 +1,197 @@
+package alice
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+)
+
+var (
+	anyResponseWriterType = reflect.TypeOf((*http.ResponseWriter)(nil)).Elem()
+	anyRequestType        = reflect.TypeOf((*http.Request)(nil))
+	anyContextType        = reflect.TypeOf((*context.Context)(nil)).Elem()
+	anyErrorType          = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// WithProvider registers fn -- a function of the form
+// func(*http.Request) (*T, error) -- as the way ThenAny resolves a *T
+// argument for handlers that ask for one, such as a session, a decoded
+// request body, or a database transaction.
+//
+// WithProvider returns a new Chain, leaving the original one untouched.
+// It panics if fn does not have the expected signature.
+func (c Chain) WithProvider(fn interface{}) Chain {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.In(0) != anyRequestType ||
+		t.NumOut() != 2 || t.Out(0).Kind() != reflect.Ptr || t.Out(1) != anyErrorType {
+		panic("alice: provider must have signature func(*http.Request) (*T, error)")
+	}
+
+	providers := make(map[reflect.Type]reflect.Value, len(c.providers)+1)
+	for k, p := range c.providers {
+		providers[k] = p
+	}
+	providers[t.Out(0)] = v
+
+	return Chain{c.constructors, c.endwares, providers}
+}
+
+// withMergedProviders returns a copy of c with extra's providers merged
+// in, preferring extra's entry on a conflicting type. It backs Extend,
+// so a WithProvider registration made on a sub-chain survives being
+// folded into another chain.
+func (c Chain) withMergedProviders(extra map[reflect.Type]reflect.Value) Chain {
+	if len(extra) == 0 {
+		return c
+	}
+
+	providers := make(map[reflect.Type]reflect.Value, len(c.providers)+len(extra))
+	for k, v := range c.providers {
+		providers[k] = v
+	}
+	for k, v := range extra {
+		providers[k] = v
+	}
+
+	return Chain{c.constructors, c.endwares, providers}
+}
+
+// ThenAny is like Then, but accepts a handler of (almost) any signature
+// rather than just http.Handler. h's signature is validated, and any
+// provider looked up, once, when ThenAny is called. An http.Handler or a
+// func(http.ResponseWriter, *http.Request) is then called directly on
+// each request; every other form is called through reflect.Value.Call
+// on each request, which costs more than a hand-written adapter. The
+// supported signatures are:
+//
+//     func(http.ResponseWriter, *http.Request)
+//     func(http.ResponseWriter, *http.Request) error
+//     func(context.Context, http.ResponseWriter, *http.Request)
+//     func(http.ResponseWriter, *http.Request, *T)
+//
+// The context and *T forms may also return an error. h may also be an
+// http.Handler, which is served as is. The *T form resolves its *T
+// argument through a provider registered on the chain with WithProvider.
+// A returned error is written to the client with http.Error.
+//
+// ThenAny panics if h is none of the above, or if it asks for a *T with
+// no matching provider registered.
+func (c Chain) ThenAny(h interface{}) http.Handler {
+	return c.Then(adaptAny(h, c.providers))
+}
+
+// ConstructorAny adapts h -- a handler accepted by ThenAny, except it
+// may not ask for a provider-resolved *T argument -- into a Constructor
+// that runs h and then calls the next handler in the chain, unless h
+// has an error-returning signature and returns a non-nil error, in
+// which case the error has already been written to w and next is not
+// called.
+func ConstructorAny(h interface{}) Constructor {
+	adapted := adaptAnyFunc(h, nil)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if adapted(w, r) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// adaptAny validates h's signature and returns an http.Handler that
+// calls it, resolving any *T argument through providers.
+func adaptAny(h interface{}, providers map[reflect.Type]reflect.Value) http.Handler {
+	fn := adaptAnyFunc(h, providers)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fn(w, r)
+	})
+}
+
+// adaptAnyFunc is like adaptAny, but returns a function reporting
+// whether processing should continue, instead of an http.Handler. It
+// returns false once h's signature resolves to a non-nil error (an
+// error has then already been written to w with http.Error), so
+// ConstructorAny can stop the chain instead of running next after a
+// failed check.
+func adaptAnyFunc(h interface{}, providers map[reflect.Type]reflect.Value) func(http.ResponseWriter, *http.Request) bool {
+	if hh, ok := h.(http.Handler); ok {
+		return func(w http.ResponseWriter, r *http.Request) bool {
+			hh.ServeHTTP(w, r)
+			return true
+		}
+	}
+	if fn, ok := h.(func(http.ResponseWriter, *http.Request)); ok {
+		return func(w http.ResponseWriter, r *http.Request) bool {
+			fn(w, r)
+			return true
+		}
+	}
+
+	v := reflect.ValueOf(h)
+	t := v.Type()
+	if t.Kind() != reflect.Func {
+		panic("alice: ThenAny/ConstructorAny require a function or http.Handler")
+	}
+
+	const badSig = "alice: ThenAny/ConstructorAny: unsupported handler signature "
+
+	var wantsCtx bool
+	var extra reflect.Type
+	switch {
+	case t.NumIn() == 2 && t.In(0) == anyResponseWriterType && t.In(1) == anyRequestType:
+		// func(w, r) [error]
+	case t.NumIn() == 3 && t.In(0) == anyContextType && t.In(1) == anyResponseWriterType && t.In(2) == anyRequestType:
+		wantsCtx = true
+	case t.NumIn() == 3 && t.In(0) == anyResponseWriterType && t.In(1) == anyRequestType && t.In(2).Kind() == reflect.Ptr:
+		extra = t.In(2)
+	default:
+		panic(badSig + t.String())
+	}
+
+	var wantsErr bool
+	switch t.NumOut() {
+	case 0:
+	case 1:
+		if t.Out(0) != anyErrorType {
+			panic(badSig + t.String())
+		}
+		wantsErr = true
+	default:
+		panic(badSig + t.String())
+	}
+
+	var provider reflect.Value
+	if extra != nil {
+		p, ok := providers[extra]
+		if !ok {
+			panic("alice: ThenAny/ConstructorAny: no provider registered for " + extra.String())
+		}
+		provider = p
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) bool {
+		var args []reflect.Value
+		switch {
+		case wantsCtx:
+			args = []reflect.Value{reflect.ValueOf(r.Context()), reflect.ValueOf(w), reflect.ValueOf(r)}
+		case extra != nil:
+			out := provider.Call([]reflect.Value{reflect.ValueOf(r)})
+			if err, _ := out[1].Interface().(error); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return false
+			}
+			args = []reflect.Value{reflect.ValueOf(w), reflect.ValueOf(r), out[0]}
+		default:
+			args = []reflect.Value{reflect.ValueOf(w), reflect.ValueOf(r)}
+		}
+
+		out := v.Call(args)
+		if wantsErr {
+			if err, _ := out[0].Interface().(error); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,163 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type session struct {
+	user string
+}
+
+func TestThenAnyPlainSignature(t *testing.T) {
+	h := New().ThenAny(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("plain\n"))
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "plain\n" {
+		t.Errorf("ThenAny did not call a func(w, r) handler: got %q", w.Body.String())
+	}
+}
+
+func TestThenAnyErrorSignature(t *testing.T) {
+	h := New().ThenAny(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("ThenAny did not write the returned error: got status %d", w.Code)
+	}
+}
+
+func TestThenAnyContextSignature(t *testing.T) {
+	type ctxKey struct{}
+	h := New().ThenAny(func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		if v, _ := ctx.Value(ctxKey{}).(string); v != "" {
+			w.Write([]byte(v))
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, "ctx\n"))
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "ctx\n" {
+		t.Errorf("ThenAny did not pass the request's context: got %q", w.Body.String())
+	}
+}
+
+func TestThenAnyProviderSignature(t *testing.T) {
+	chain := New().WithProvider(func(r *http.Request) (*session, error) {
+		return &session{user: "gopher"}, nil
+	})
+
+	h := chain.ThenAny(func(w http.ResponseWriter, r *http.Request, s *session) {
+		w.Write([]byte(s.user))
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "gopher" {
+		t.Errorf("ThenAny did not resolve the provider-backed argument: got %q", w.Body.String())
+	}
+}
+
+func TestThenAnyPanicsWithoutProvider(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ThenAny should panic when no provider is registered for the requested type")
+		}
+	}()
+
+	New().ThenAny(func(w http.ResponseWriter, r *http.Request, s *session) {})
+}
+
+func TestThenAnyPanicsOnUnsupportedSignature(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ThenAny should panic on an unsupported handler signature")
+		}
+	}()
+
+	New().ThenAny(func(a, b, c int) {})
+}
+
+func TestConstructorAnyRunsHandlerBeforeNext(t *testing.T) {
+	h := New(ConstructorAny(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("before\n"))
+	})).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "before\napp\n" {
+		t.Errorf("ConstructorAny did not run the handler before next: got %q", w.Body.String())
+	}
+}
+
+func TestConstructorAnyStopsChainOnError(t *testing.T) {
+	h := New(ConstructorAny(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("unauthorized")
+	})).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("ConstructorAny did not write the returned error: got status %d", w.Code)
+	}
+	if w.Body.String() != "unauthorized\n" {
+		t.Errorf("ConstructorAny ran next after a failed check: got %q", w.Body.String())
+	}
+}
+
+func TestWithProviderPreservesConstructorsAndEndwares(t *testing.T) {
+	chain := New(tagMiddleware("m1\n")).After(tagEndware("e1\n")).
+		WithProvider(func(r *http.Request) (*session, error) { return &session{}, nil })
+
+	h := chain.ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "m1\napp\ne1\n" {
+		t.Errorf("WithProvider dropped constructors or endwares: got %q", w.Body.String())
+	}
+}
+
+func TestExtendPreservesProviders(t *testing.T) {
+	sub := New().WithProvider(func(r *http.Request) (*session, error) {
+		return &session{user: "gopher"}, nil
+	})
+
+	chain := New(tagMiddleware("m1\n")).Extend(sub)
+
+	h := chain.ThenAny(func(w http.ResponseWriter, r *http.Request, s *session) {
+		w.Write([]byte(s.user))
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "m1\ngopher" {
+		t.Errorf("Extend dropped the sub-chain's providers: got %q", w.Body.String())
+	}
+}
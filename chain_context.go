@@ -0,0 +1,33 @@
+package alice
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithContext returns a new chain that derives a context.Context from
+// each incoming request via fn, and attaches it to the request (via
+// r.WithContext) before passing it further down the chain.
+//
+// WithContext supersedes Contextualise and the ContextualisedChain type:
+// those predate *http.Request.Context() and carry a whole parallel type
+// hierarchy (ContextualisedConstructor, ContextualisedHandler,
+// ContextualisedHandlerFunc, ContextualisedChain) built on
+// golang.org/x/net/context, just to get a context past a boundary the
+// standard library now crosses on its own. A WithContext call is just
+// another constructor, so it composes with Append, Extend, After and
+// ThenAny like any other part of the chain.
+//
+//     chain := alice.New(m1).
+//         WithContext(func(r *http.Request) context.Context {
+//             return context.WithValue(r.Context(), userKey, currentUser(r))
+//         }).
+//         Append(m2)
+//     // requests go m1 -> (inject context) -> m2 -> handler
+func (c Chain) WithContext(fn func(*http.Request) context.Context) Chain {
+	return c.Append(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(fn(r)))
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package alice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type userCtxKey struct{}
+
+func TestWithContextInjectsContextForDownstreamHandlers(t *testing.T) {
+	chain := New(tagMiddleware("m1\n")).
+		WithContext(func(r *http.Request) context.Context {
+			return context.WithValue(r.Context(), userCtxKey{}, "gopher")
+		}).
+		Append(tagMiddleware("m2\n"))
+
+	h := chain.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Context().Value(userCtxKey{}).(string)))
+	})
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "m1\nm2\ngopher" {
+		t.Errorf("WithContext did not inject the context for the rest of the chain: got %q", w.Body.String())
+	}
+}
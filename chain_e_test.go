@@ -0,0 +1,138 @@
+package alice
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func tagMiddlewareE(tag string) ConstructorE {
+	return func(h http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tag))
+			h.ServeHTTP(w, r)
+		}), nil
+	}
+}
+
+func failingMiddlewareE(err error) ConstructorE {
+	return func(h http.Handler) (http.Handler, error) {
+		return nil, err
+	}
+}
+
+func TestNewE(t *testing.T) {
+	c1 := func(h http.Handler) (http.Handler, error) { return h, nil }
+	c2 := func(h http.Handler) (http.Handler, error) { return h, nil }
+
+	slice := []ConstructorE{c1, c2}
+	chain := NewE(slice...)
+
+	for k := range slice {
+		if !funcsEqual(chain.constructors[k], slice[k]) {
+			t.Error("NewE does not add constructors correctly")
+		}
+	}
+}
+
+func TestThenETreatsNilAsDefaultServeMux(t *testing.T) {
+	h, err := NewE().ThenE(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h != http.DefaultServeMux {
+		t.Error("ThenE does not treat nil as DefaultServeMux")
+	}
+}
+
+func TestThenEOrdersHandlersCorrectly(t *testing.T) {
+	h, err := NewE(
+		tagMiddlewareE("t1\n"),
+		tagMiddlewareE("t2\n"),
+		tagMiddlewareE("t3\n"),
+	).ThenE(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "t1\nt2\nt3\napp\n" {
+		t.Errorf("ThenE does not order handlers correctly: got %q", w.Body.String())
+	}
+}
+
+func TestThenEPropagatesConstructorError(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	h, err := NewE(
+		tagMiddlewareE("t1\n"),
+		failingMiddlewareE(wantErr),
+		tagMiddlewareE("t2\n"),
+	).ThenE(testApp)
+
+	if err != wantErr {
+		t.Errorf("ThenE returned error %v, want %v", err, wantErr)
+	}
+	if h != nil {
+		t.Error("ThenE should return a nil http.Handler on error")
+	}
+}
+
+func TestAppendEAddsHandlersCorrectly(t *testing.T) {
+	chain := NewE(tagMiddlewareE("t1\n"), tagMiddlewareE("t2\n"))
+	newChain := chain.AppendE(tagMiddlewareE("t3\n"), tagMiddlewareE("t4\n"))
+
+	if len(chain.constructors) != 2 {
+		t.Error("chain should have 2 constructors")
+	}
+	if len(newChain.constructors) != 4 {
+		t.Error("newChain should have 4 constructors")
+	}
+}
+
+func TestExtendEAddsHandlersCorrectly(t *testing.T) {
+	chain1 := NewE(tagMiddlewareE("t1\n"), tagMiddlewareE("t2\n"))
+	chain2 := NewE(tagMiddlewareE("t3\n"), tagMiddlewareE("t4\n"))
+	newChain := chain1.ExtendE(chain2)
+
+	h, err := newChain.ThenE(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "t1\nt2\nt3\nt4\napp\n" {
+		t.Errorf("ExtendE does not add handlers correctly: got %q", w.Body.String())
+	}
+}
+
+func TestToConstructorEAdaptsConstructor(t *testing.T) {
+	h, err := NewE(ToConstructorE(tagMiddleware("t1\n"))).ThenE(testApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r, reqErr := http.NewRequest("GET", "/", nil)
+	if reqErr != nil {
+		t.Fatal(reqErr)
+	}
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "t1\napp\n" {
+		t.Errorf("ToConstructorE did not adapt the Constructor correctly: got %q", w.Body.String())
+	}
+}
@@ -4,11 +4,18 @@ import "net/http"
 
 //ToContextConstructor allows you to chain a non contextualized
 //http handler with a contextualized one.
+//
+// Deprecated: use Chain.WithContext instead.
 type ToContextConstructor func(ContextualisedHandler) http.Handler
 
 // Contextualise allows you to append a contextualized http handler
 // to your normal chain thus allowing to add ctx support to all
 // subsequent http handlers.
+//
+// Deprecated: use Chain.WithContext, which injects a context.Context via
+// the standard r.WithContext and keeps the rest of the chain as plain
+// http.Handlers, instead of switching to the ContextualisedHandler
+// hierarchy.
 func (c Chain) Contextualise(transformer ToContextConstructor) (cc toContextualisedChain) {
 	return toContextualisedChain{
 		chain:       c.copy(),
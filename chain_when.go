@@ -0,0 +1,74 @@
+package alice
+
+import "net/http"
+
+// When returns a new chain that applies sub's constructors only to
+// requests for which pred returns true. Requests for which pred returns
+// false bypass sub's constructors entirely and continue straight to the
+// rest of the chain.
+//
+// Both branches -- the handler wrapped by sub and the bare bypass handler
+// -- are materialized once, when Then() is called, so When preserves
+// Chain's guarantee that constructors only run at chain-build time, never
+// per request. This lets a single chain apply middleware conditionally,
+// e.g. auth and rate limiting only for "/api/", without building several
+// parallel chains and dispatching between them externally.
+//
+//     stdChain := alice.New(m1, m2).
+//         When(isAPIRequest, alice.New(auth, ratelimit))
+//     // requests matching isAPIRequest go m1 -> m2 -> auth -> ratelimit -> handler
+//     // other requests go                m1 -> m2 -> handler
+func (c Chain) When(pred func(*http.Request) bool, sub Chain) Chain {
+	return c.Append(whenConstructor(pred, sub))
+}
+
+// WhenFunc works identically to When, but builds the sub-chain from a
+// plain list of constructors instead of a Chain.
+func (c Chain) WhenFunc(pred func(*http.Request) bool, constructors ...Constructor) Chain {
+	return c.When(pred, New(constructors...))
+}
+
+// When returns a Constructor that applies cs only to requests for which
+// match returns true; requests for which it returns false bypass cs
+// entirely and continue straight to the next constructor. Unlike
+// Chain.When, which gates an entire sub-chain already attached to a
+// Chain, When produces a single Constructor, so it can be passed to
+// New() or Append() alongside regular middleware.
+//
+// Both branches -- the handler wrapped by cs and the bare bypass handler
+// -- are materialized once, when Then() is called, so When allocates
+// nothing per request and the chain remains immutable.
+//
+//     alice.New(
+//         alice.When(match.MatchPathPrefix("/admin"), auth, ratelimit),
+//         gzip,
+//     ).Then(handler)
+func When(match func(*http.Request) bool, cs ...Constructor) Constructor {
+	return whenConstructor(match, New(cs...))
+}
+
+// WhenEndware returns an Endware that runs es, in order, only for
+// requests for which match returns true.
+func WhenEndware(match func(*http.Request) bool, es ...Endware) Endware {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !match(r) {
+			return
+		}
+		for _, e := range es {
+			e.ServeHTTP(w, r)
+		}
+	})
+}
+
+func whenConstructor(pred func(*http.Request) bool, sub Chain) Constructor {
+	return func(h http.Handler) http.Handler {
+		matched := sub.Then(h)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if pred(r) {
+				matched.ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
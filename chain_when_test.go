@@ -0,0 +1,113 @@
+package alice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWhenRunsSubChainOnlyWhenPredicateMatches(t *testing.T) {
+	isAPI := func(r *http.Request) bool {
+		return r.URL.Path == "/api"
+	}
+
+	chain := New(tagMiddleware("m1\n")).
+		When(isAPI, New(tagMiddleware("sub\n")))
+
+	h := chain.ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "m1\nsub\napp\n" {
+		t.Errorf("When did not run sub-chain for a matching request: got %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r, err = http.NewRequest("GET", "/other", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "m1\napp\n" {
+		t.Errorf("When ran sub-chain for a non-matching request: got %q", w.Body.String())
+	}
+}
+
+func TestWhenConstructorAppliesOnlyWhenMatched(t *testing.T) {
+	isAPI := func(r *http.Request) bool {
+		return r.URL.Path == "/api"
+	}
+
+	h := New(
+		When(isAPI, tagMiddleware("auth\n"), tagMiddleware("ratelimit\n")),
+		tagMiddleware("gzip\n"),
+	).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "auth\nratelimit\ngzip\napp\n" {
+		t.Errorf("When did not apply its constructors for a matching request: got %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r, err = http.NewRequest("GET", "/other", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "gzip\napp\n" {
+		t.Errorf("When applied its constructors for a non-matching request: got %q", w.Body.String())
+	}
+}
+
+func TestWhenEndwareRunsOnlyWhenMatched(t *testing.T) {
+	isAPI := func(r *http.Request) bool {
+		return r.URL.Path == "/api"
+	}
+
+	h := New().After(WhenEndware(isAPI, tagEndware("log\n"))).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "app\nlog\n" {
+		t.Errorf("WhenEndware did not run for a matching request: got %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r, err = http.NewRequest("GET", "/other", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "app\n" {
+		t.Errorf("WhenEndware ran for a non-matching request: got %q", w.Body.String())
+	}
+}
+
+func TestWhenFuncBuildsSubChainFromConstructors(t *testing.T) {
+	matchAll := func(r *http.Request) bool { return true }
+
+	h := New().WhenFunc(matchAll, tagMiddleware("t1\n"), tagMiddleware("t2\n")).ThenFunc(testApp)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+	if w.Body.String() != "t1\nt2\napp\n" {
+		t.Errorf("WhenFunc did not chain constructors correctly: got %q", w.Body.String())
+	}
+}
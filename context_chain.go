@@ -7,14 +7,23 @@ import (
 )
 
 //ContextualisedConstructor is a Constructor with a context
+//
+// Deprecated: use Chain.WithContext, which attaches a context.Context to
+// the request via the standard r.WithContext instead of a parallel
+// handler hierarchy built on golang.org/x/net/context.
 type ContextualisedConstructor func(ContextualisedHandler) ContextualisedHandler
 
 //ContextualisedHandler is a http.Handler with a context
+//
+// Deprecated: use Chain.WithContext and a regular http.Handler that
+// reads *http.Request.Context() instead.
 type ContextualisedHandler interface {
 	ServeHTTPC(context.Context, http.ResponseWriter, *http.Request)
 }
 
 //ContextualisedHandlerFunc is a http.HandlerFunc with a context
+//
+// Deprecated: use Chain.WithContext and http.HandlerFunc instead.
 type ContextualisedHandlerFunc func(context.Context, http.ResponseWriter, *http.Request)
 
 //ServeHTTPC is like serve http but with a context
@@ -24,12 +33,18 @@ func (f ContextualisedHandlerFunc) ServeHTTPC(ctx context.Context, w http.Respon
 
 //ContextualisedChain is a chain of contextualised handlers
 //it behaves just like Chain
+//
+// Deprecated: use Chain.WithContext instead. ContextualisedChain and
+// Chain.Contextualise predate *http.Request.Context() and exist only for
+// backward compatibility; they will be removed in a future release.
 type ContextualisedChain struct {
 	constructors []ContextualisedConstructor
 }
 
 //NewContextualised instantiates a new Chain of contextualised http handlers
 //Just like New
+//
+// Deprecated: use alice.New and Chain.WithContext instead.
 func NewContextualised(constructors ...ContextualisedConstructor) (cc ContextualisedChain) {
 	cc.constructors = append(cc.constructors, constructors...)
 	return
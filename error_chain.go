@@ -0,0 +1,101 @@
+package alice
+
+import (
+	"context"
+	"net/http"
+)
+
+// ErrorHandler is like http.Handler's ServeHTTP, but may fail. Returning
+// a non-nil error lets a single, centralized handler decide how to turn
+// it into a response, instead of every middleware or handler writing one
+// inline.
+type ErrorHandler func(http.ResponseWriter, *http.Request) error
+
+// ErrorConstructor is to ErrorChain what Constructor is to Chain.
+type ErrorConstructor func(ErrorHandler) ErrorHandler
+
+// ErrorChain acts as a list of ErrorHandler constructors.
+// ErrorChain is effectively immutable:
+// once created, it will always hold
+// the same set of constructors in the same order.
+type ErrorChain struct {
+	constructors []ErrorConstructor
+}
+
+// NewError creates a new ErrorChain,
+// memorizing the given list of middleware constructors.
+// NewError serves no other function,
+// constructors are only called upon a call to Then().
+func NewError(constructors ...ErrorConstructor) ErrorChain {
+	return ErrorChain{append(([]ErrorConstructor)(nil), constructors...)}
+}
+
+type errorChainCtxKey struct{}
+
+// ErrorFromContext returns the error that Then's terminal adapter stashed
+// on the request's context, or nil if the request completed without one.
+// Endware added via Chain.After can call this to log or report the error
+// that errFn already turned into a response.
+func ErrorFromContext(ctx context.Context) error {
+	err, _ := ctx.Value(errorChainCtxKey{}).(error)
+	return err
+}
+
+// Then chains the middleware and returns the final http.Handler.
+//     NewError(m1, m2, m3).Then(eh, errFn)
+// is equivalent to:
+//     m1(m2(m3(eh)))
+// with errFn handling any error that comes back.
+// When the request comes in, it will be passed to m1, then m2, then m3,
+// then the given ErrorHandler. If any of them returns an error, the chain
+// stops there and the error is passed to errFn, which is responsible for
+// writing a response for it.
+//
+// Before errFn runs, the error is stashed on the request's context
+// (retrievable with ErrorFromContext), so the returned http.Handler can
+// still be wrapped in an alice.Chain: its endwares will fire as usual and
+// can read the error to log it, without having to abandon the regular
+// composition model.
+//
+// Then() treats a nil ErrorHandler as one that always succeeds without
+// writing a response.
+func (c ErrorChain) Then(eh ErrorHandler, errFn func(http.ResponseWriter, *http.Request, error)) http.Handler {
+	if eh == nil {
+		eh = func(w http.ResponseWriter, r *http.Request) error { return nil }
+	}
+
+	for i := range c.constructors {
+		eh = c.constructors[len(c.constructors)-1-i](eh)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := eh(w, r); err != nil {
+			// Mutate *r in place, rather than rebind the local r, so that
+			// endware added via Chain.After -- which receives the same
+			// *http.Request pointer but runs after this handler returns --
+			// can still retrieve the error through ErrorFromContext.
+			*r = *r.WithContext(context.WithValue(r.Context(), errorChainCtxKey{}, err))
+			errFn(w, r, err)
+		}
+	})
+}
+
+// Append extends a chain, adding the specified constructors
+// as the last ones in the request flow.
+//
+// Append returns a new chain, leaving the original one untouched.
+func (c ErrorChain) Append(constructors ...ErrorConstructor) ErrorChain {
+	newCons := make([]ErrorConstructor, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return ErrorChain{newCons}
+}
+
+// Extend extends a chain by adding the specified chain
+// as the last one in the request flow.
+//
+// Extend returns a new chain, leaving the original one untouched.
+func (c ErrorChain) Extend(chain ErrorChain) ErrorChain {
+	return c.Append(chain.constructors...)
+}
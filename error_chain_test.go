@@ -0,0 +1,98 @@
+package alice
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func tagErrorMiddleware(tag string) ErrorConstructor {
+	return func(eh ErrorHandler) ErrorHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			w.Write([]byte(tag))
+			return eh(w, r)
+		}
+	}
+}
+
+func TestErrorChainThenRunsHandlersInOrder(t *testing.T) {
+	eh := func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("app\n"))
+		return nil
+	}
+
+	h := NewError(tagErrorMiddleware("t1\n"), tagErrorMiddleware("t2\n")).
+		Then(eh, func(w http.ResponseWriter, r *http.Request, err error) {
+			t.Fatalf("errFn should not be called, got error: %v", err)
+		})
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+
+	if w.Body.String() != "t1\nt2\napp\n" {
+		t.Errorf("Then did not run ErrorHandlers in order: got %q", w.Body.String())
+	}
+}
+
+func TestErrorChainThenCallsErrFnOnError(t *testing.T) {
+	wantErr := errors.New("boom")
+	eh := func(w http.ResponseWriter, r *http.Request) error {
+		return wantErr
+	}
+
+	var gotErr error
+	var ctxErr error
+	h := NewError().Then(eh, func(w http.ResponseWriter, r *http.Request, err error) {
+		gotErr = err
+		ctxErr = ErrorFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+
+	if gotErr != wantErr {
+		t.Errorf("errFn received error %v, want %v", gotErr, wantErr)
+	}
+	if ctxErr != wantErr {
+		t.Errorf("ErrorFromContext returned %v, want %v", ctxErr, wantErr)
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("errFn's response was not applied: got status %d", w.Code)
+	}
+}
+
+func TestErrorChainThenComposesWithChainEndware(t *testing.T) {
+	eh := func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	}
+
+	var loggedErr error
+	errHandler := NewError().Then(eh, func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	h := New().After(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loggedErr = ErrorFromContext(r.Context())
+	})).Then(errHandler)
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeHTTP(w, r)
+
+	if loggedErr == nil || loggedErr.Error() != "boom" {
+		t.Errorf("endware did not observe the error via ErrorFromContext, got %v", loggedErr)
+	}
+}
@@ -0,0 +1,47 @@
+// Package match provides a handful of ready-made request matchers for
+// use with alice.Chain.When and alice.When.
+package match
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MatchPath returns a matcher that matches requests whose URL path is
+// exactly path.
+func MatchPath(path string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return r.URL.Path == path
+	}
+}
+
+// MatchPathPrefix returns a matcher that matches requests whose URL path
+// starts with prefix.
+func MatchPathPrefix(prefix string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+}
+
+// MatchMethod returns a matcher that matches requests made with the
+// given HTTP method.
+func MatchMethod(method string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return r.Method == method
+	}
+}
+
+// MatchHost returns a matcher that matches requests whose Host is host.
+func MatchHost(host string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return r.Host == host
+	}
+}
+
+// MatchHeader returns a matcher that matches requests carrying a header
+// named key whose value is value.
+func MatchHeader(key, value string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return r.Header.Get(key) == value
+	}
+}
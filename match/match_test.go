@@ -0,0 +1,58 @@
+package match
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/foo", nil)
+	if !MatchPath("/foo")(r) {
+		t.Error("MatchPath should match an identical path")
+	}
+	if MatchPath("/bar")(r) {
+		t.Error("MatchPath should not match a different path")
+	}
+}
+
+func TestMatchPathPrefix(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/api/users", nil)
+	if !MatchPathPrefix("/api")(r) {
+		t.Error("MatchPathPrefix should match a path with the given prefix")
+	}
+	if MatchPathPrefix("/admin")(r) {
+		t.Error("MatchPathPrefix should not match a path without the given prefix")
+	}
+}
+
+func TestMatchMethod(t *testing.T) {
+	r, _ := http.NewRequest("POST", "/", nil)
+	if !MatchMethod("POST")(r) {
+		t.Error("MatchMethod should match a request with the given method")
+	}
+	if MatchMethod("GET")(r) {
+		t.Error("MatchMethod should not match a request with a different method")
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	r, _ := http.NewRequest("GET", "http://example.com/", nil)
+	r.Host = "example.com"
+	if !MatchHost("example.com")(r) {
+		t.Error("MatchHost should match a request with the given host")
+	}
+	if MatchHost("other.com")(r) {
+		t.Error("MatchHost should not match a request with a different host")
+	}
+}
+
+func TestMatchHeader(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	if !MatchHeader("Accept-Encoding", "gzip")(r) {
+		t.Error("MatchHeader should match a request with the given header value")
+	}
+	if MatchHeader("Accept-Encoding", "br")(r) {
+		t.Error("MatchHeader should not match a request with a different header value")
+	}
+}
@@ -0,0 +1,183 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// A constructor for middleware
+// that writes its own "tag" into buf and does nothing else.
+// Useful in checking if a chain is behaving in the right order.
+func tagMiddleware(buf *bytes.Buffer, tag string) Constructor {
+	return func(h Handler) (Handler, error) {
+		return HandlerFunc(func(conn net.Conn) {
+			buf.WriteString(tag)
+			h.ServeTCP(conn)
+		}), nil
+	}
+}
+
+// A constructor that always fails to build its Handler.
+func failingMiddleware(err error) Constructor {
+	return func(h Handler) (Handler, error) {
+		return nil, err
+	}
+}
+
+// Not recommended (https://golang.org/pkg/reflect/#Value.Pointer),
+// but the best we can do.
+func funcsEqual(f1, f2 interface{}) bool {
+	val1 := reflect.ValueOf(f1)
+	val2 := reflect.ValueOf(f2)
+	return val1.Pointer() == val2.Pointer()
+}
+
+func TestNew(t *testing.T) {
+	c1 := func(h Handler) (Handler, error) {
+		return nil, nil
+	}
+
+	c2 := func(h Handler) (Handler, error) {
+		return h, nil
+	}
+
+	slice := []Constructor{c1, c2}
+
+	chain := New(slice...)
+	for k := range slice {
+		if !funcsEqual(chain.constructors[k], slice[k]) {
+			t.Error("New does not add constructors correctly")
+		}
+	}
+}
+
+func TestThenWorksWithNoMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	app := HandlerFunc(func(conn net.Conn) { buf.WriteString("app\n") })
+
+	h, err := New().Then(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.ServeTCP(nil)
+	if buf.String() != "app\n" {
+		t.Error("Then does not work with no middleware")
+	}
+}
+
+func TestThenOrdersHandlersCorrectly(t *testing.T) {
+	buf := &bytes.Buffer{}
+	app := HandlerFunc(func(conn net.Conn) { buf.WriteString("app\n") })
+
+	h, err := New(
+		tagMiddleware(buf, "t1\n"),
+		tagMiddleware(buf, "t2\n"),
+		tagMiddleware(buf, "t3\n"),
+	).Then(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h.ServeTCP(nil)
+
+	if buf.String() != "t1\nt2\nt3\napp\n" {
+		t.Error("Then does not order handlers correctly")
+	}
+}
+
+func TestThenPropagatesConstructorError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	wantErr := errors.New("boom")
+	app := HandlerFunc(func(conn net.Conn) { buf.WriteString("app\n") })
+
+	h, err := New(
+		// Writes to buf when constructed, so buf stays empty only if Then
+		// never reaches this head constructor.
+		func(h Handler) (Handler, error) {
+			buf.WriteString("built t1\n")
+			return h, nil
+		},
+		failingMiddleware(wantErr),
+		tagMiddleware(buf, "t2\n"),
+	).Then(app)
+
+	if err != wantErr {
+		t.Errorf("Then returned error %v, want %v", err, wantErr)
+	}
+	if h != nil {
+		t.Error("Then should return a nil Handler on error")
+	}
+	if buf.Len() != 0 {
+		t.Error("Then should stop building the chain once a constructor fails")
+	}
+}
+
+func TestAppendAddsHandlersCorrectly(t *testing.T) {
+	buf := &bytes.Buffer{}
+	chain := New(tagMiddleware(buf, "t1\n"), tagMiddleware(buf, "t2\n"))
+	newChain := chain.Append(tagMiddleware(buf, "t3\n"), tagMiddleware(buf, "t4\n"))
+
+	if len(chain.constructors) != 2 {
+		t.Error("chain should have 2 constructors")
+	}
+	if len(newChain.constructors) != 4 {
+		t.Error("newChain should have 4 constructors")
+	}
+
+	app := HandlerFunc(func(conn net.Conn) { buf.WriteString("app\n") })
+	h, err := newChain.Then(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeTCP(nil)
+
+	if buf.String() != "t1\nt2\nt3\nt4\napp\n" {
+		t.Error("Append does not add handlers correctly")
+	}
+}
+
+func TestAppendRespectsImmutability(t *testing.T) {
+	buf := &bytes.Buffer{}
+	chain := New(tagMiddleware(buf, ""))
+	newChain := chain.Append(tagMiddleware(buf, ""))
+
+	if &chain.constructors[0] == &newChain.constructors[0] {
+		t.Error("Append does not respect immutability")
+	}
+}
+
+func TestExtendAddsHandlersCorrectly(t *testing.T) {
+	buf := &bytes.Buffer{}
+	chain1 := New(tagMiddleware(buf, "t1\n"), tagMiddleware(buf, "t2\n"))
+	chain2 := New(tagMiddleware(buf, "t3\n"), tagMiddleware(buf, "t4\n"))
+	newChain := chain1.Extend(chain2)
+
+	if len(newChain.constructors) != 4 {
+		t.Error("newChain should contain 4 constructors")
+	}
+
+	app := HandlerFunc(func(conn net.Conn) { buf.WriteString("app\n") })
+	h, err := newChain.Then(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ServeTCP(nil)
+
+	if buf.String() != "t1\nt2\nt3\nt4\napp\n" {
+		t.Error("Extend does not add handlers in correctly")
+	}
+}
+
+func TestExtendRespectsImmutability(t *testing.T) {
+	buf := &bytes.Buffer{}
+	chain := New(tagMiddleware(buf, ""))
+	newChain := chain.Extend(New(tagMiddleware(buf, "")))
+
+	if &chain.constructors[0] == &newChain.constructors[0] {
+		t.Error("Extend does not respect immutability")
+	}
+}
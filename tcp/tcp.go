@@ -0,0 +1,128 @@
+// Package tcp provides a convenient way to chain raw TCP connection
+// handlers. It supersedes the older stream package: Handler here is
+// built on WriteCloser instead of a plain net.Conn, so middleware can
+// half-close a connection's write side -- something TLS termination and
+// proxy-protocol parsing both need and stream.Handler cannot express.
+package tcp
+
+import "net"
+
+// WriteCloser is a net.Conn that can additionally half-close its write
+// side. Middleware like TLS termination or a proxy-protocol parser needs
+// this to signal "no more data" to the peer while still reading a
+// response, which plain net.Conn cannot express.
+type WriteCloser interface {
+	net.Conn
+	CloseWrite() error
+}
+
+// Handler responds to a single accepted TCP connection.
+type Handler interface {
+	ServeTCP(conn WriteCloser)
+}
+
+// HandlerFunc is to Handler what http.HandlerFunc is to http.Handler.
+type HandlerFunc func(WriteCloser)
+
+// ServeTCP calls f(conn).
+func (f HandlerFunc) ServeTCP(conn WriteCloser) {
+	f(conn)
+}
+
+// Constructor for a piece of middleware.
+// Unlike alice.Constructor, it may fail to produce a Handler -- this
+// lets middleware that dials upstreams or parses TLS configuration
+// (proxy protocol parsing, TLS SNI routing, mTLS) reject a chain at
+// construction time, rather than having to fail once a connection is
+// already being served.
+type Constructor func(Handler) (Handler, error)
+
+// Chain acts as a list of Handler constructors.
+// Chain is effectively immutable:
+// once created, it will always hold
+// the same set of constructors in the same order.
+type Chain struct {
+	constructors []Constructor
+}
+
+// New creates a new chain,
+// memorizing the given list of middleware constructors.
+// New serves no other function,
+// constructors are only called upon a call to Then().
+func New(constructors ...Constructor) Chain {
+	return Chain{append(([]Constructor)(nil), constructors...)}
+}
+
+// Then chains the middleware and returns the final Handler.
+//     New(m1, m2, m3).Then(h)
+// is equivalent to:
+//     m1(m2(m3(h)))
+// When a connection is accepted, it will be passed to m1, then m2, then
+// m3 and finally to the given handler (assuming every middleware calls
+// the following one).
+//
+// Unlike alice.Chain.Then, Then can fail: constructors are applied
+// tail-to-head and, if any of them returns an error, Then stops
+// immediately and returns that error together with a nil Handler.
+//
+// A chain can be safely reused by calling Then() several times.
+// Note that constructors are called on every call to Then()
+// and thus several instances of the same middleware will be created
+// when a chain is reused in this way.
+func (c Chain) Then(h Handler) (Handler, error) {
+	var err error
+	for i := range c.constructors {
+		h, err = c.constructors[len(c.constructors)-1-i](h)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// ThenFunc works identically to Then, but takes
+// a HandlerFunc instead of a Handler.
+//
+// The following two statements are equivalent:
+//     c.Then(HandlerFunc(fn))
+//     c.ThenFunc(fn)
+//
+// ThenFunc provides all the guarantees of Then.
+func (c Chain) ThenFunc(fn HandlerFunc) (Handler, error) {
+	if fn == nil {
+		return c.Then(nil)
+	}
+	return c.Then(fn)
+}
+
+// Append extends a chain, adding the specified constructors
+// as the last ones in the request flow.
+//
+// Append returns a new chain, leaving the original one untouched.
+//
+//     stdChain := tcp.New(m1, m2)
+//     extChain := stdChain.Append(m3, m4)
+//     // connections in stdChain go m1 -> m2
+//     // connections in extChain go m1 -> m2 -> m3 -> m4
+func (c Chain) Append(constructors ...Constructor) Chain {
+	newCons := make([]Constructor, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return Chain{newCons}
+}
+
+// Extend extends a chain by adding the specified chain
+// as the last one in the request flow.
+//
+// Extend returns a new chain, leaving the original one untouched.
+//
+//     stdChain := tcp.New(m1, m2)
+//     ext1Chain := tcp.New(m3, m4)
+//     ext2Chain := stdChain.Extend(ext1Chain)
+//     // connections in stdChain  go m1 -> m2
+//     // connections in ext1Chain go m3 -> m4
+//     // connections in ext2Chain go m1 -> m2 -> m3 -> m4
+func (c Chain) Extend(chain Chain) Chain {
+	return c.Append(chain.constructors...)
+}